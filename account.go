@@ -0,0 +1,96 @@
+package main
+
+import "sync"
+
+// Account is a single customer balance that is safe for concurrent use.
+// Once closed, every operation (including Balance) reports ok=false
+// instead of panicking or silently mutating a closed account.
+type Account struct {
+	mu      sync.Mutex
+	balance int
+	closed  bool
+}
+
+// Open creates an Account with the given initial deposit. It returns nil if
+// initialDeposit is negative.
+func Open(initialDeposit int) *Account {
+	if initialDeposit < 0 {
+		return nil
+	}
+	return &Account{balance: initialDeposit}
+}
+
+// Balance reports the current balance. ok is false if the account is
+// closed.
+func (a *Account) Balance() (amount int, ok bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return 0, false
+	}
+	return a.balance, true
+}
+
+// Deposit credits amount and returns the new balance. ok is false if the
+// account is closed.
+func (a *Account) Deposit(amount int) (newBalance int, ok bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return 0, false
+	}
+	a.balance += amount
+	return a.balance, true
+}
+
+// Withdraw debits amount and returns the new balance. ok is false if the
+// account is closed or the balance is insufficient.
+func (a *Account) Withdraw(amount int) (newBalance int, ok bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed || a.balance < amount {
+		return 0, false
+	}
+	a.balance -= amount
+	return a.balance, true
+}
+
+// Close closes the account, paying out whatever balance remained. A second
+// call to Close (or any call after the account is already closed) returns
+// ok=false rather than paying out twice.
+func (a *Account) Close() (payout int, ok bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return 0, false
+	}
+	a.closed = true
+	return a.balance, true
+}
+
+// ============================================
+// Wiring: closed accounts short-circuit the transaction processor
+// ============================================
+
+// accounts tracks the Account registered for each CustomerID.
+// transactionProcessor looks every transaction's CustomerID up here and
+// rejects it if that Account is closed; testMutex registers its demo
+// account here but checks the closed state through the Account's own
+// methods, not through this map. Transactions for a CustomerID with no
+// entry here behave exactly as before (no closed-account check).
+var accounts = map[string]*Account{}
+var accountsMu sync.Mutex
+
+func registerAccount(customerID string, acct *Account) {
+	accountsMu.Lock()
+	defer accountsMu.Unlock()
+	accounts[customerID] = acct
+}
+
+// lookupAccount returns the Account registered for customerID, if any.
+func lookupAccount(customerID string) (*Account, bool) {
+	accountsMu.Lock()
+	defer accountsMu.Unlock()
+	acct, ok := accounts[customerID]
+	return acct, ok
+}
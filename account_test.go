@@ -0,0 +1,109 @@
+package main
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestOpenRejectsNegativeDeposit(t *testing.T) {
+	if acct := Open(-1); acct != nil {
+		t.Fatalf("Open(-1) = %+v, want nil", acct)
+	}
+}
+
+func TestClosedAccountRejectsEveryOp(t *testing.T) {
+	acct := Open(100)
+	if payout, ok := acct.Close(); !ok || payout != 100 {
+		t.Fatalf("Close = (%d, %v), want (100, true)", payout, ok)
+	}
+
+	if _, ok := acct.Balance(); ok {
+		t.Fatal("Balance on closed account returned ok=true")
+	}
+	if _, ok := acct.Deposit(10); ok {
+		t.Fatal("Deposit on closed account returned ok=true")
+	}
+	if _, ok := acct.Withdraw(10); ok {
+		t.Fatal("Withdraw on closed account returned ok=true")
+	}
+}
+
+func TestCloseIsNotIdempotent(t *testing.T) {
+	acct := Open(50)
+	if _, ok := acct.Close(); !ok {
+		t.Fatal("first Close: ok = false, want true")
+	}
+	if payout, ok := acct.Close(); ok {
+		t.Fatalf("second Close = (%d, %v), want ok=false", payout, ok)
+	}
+}
+
+// TestAccountConcurrentUse drives concurrent Deposit/Withdraw calls against
+// a single Account and checks the balance lands exactly where the
+// successful calls say it should, with no panics — run with -race to
+// confirm the mutex actually serializes access.
+func TestAccountConcurrentUse(t *testing.T) {
+	acct := Open(1000)
+
+	var wg sync.WaitGroup
+	var deposited, withdrawn int
+	var mu sync.Mutex
+
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, ok := acct.Deposit(10); ok {
+				mu.Lock()
+				deposited += 10
+				mu.Unlock()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, ok := acct.Withdraw(5); ok {
+				mu.Lock()
+				withdrawn += 5
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	want := 1000 + deposited - withdrawn
+	if got, ok := acct.Balance(); !ok || got != want {
+		t.Fatalf("Balance = (%d, %v), want (%d, true)", got, ok, want)
+	}
+}
+
+// TestTransactionProcessorRejectsClosedAccount covers the short-circuit
+// wired into transactionProcessor: a closed account's transaction must be
+// rejected without touching the ledger.
+func TestTransactionProcessorRejectsClosedAccount(t *testing.T) {
+	acct := Open(100)
+	registerAccount("CUST-TEST-CLOSED", acct)
+	if _, ok := acct.Close(); !ok {
+		t.Fatal("Close: ok = false, want true")
+	}
+
+	ledger := Ledger{CustomerBalance: 1000, BankBalance: 5000}
+	txChan := make(chan Transaction)
+	done := make(chan struct{})
+	go func() {
+		transactionProcessor(&ledger, txChan)
+		close(done)
+	}()
+
+	txChan <- Transaction{
+		Amount:     50,
+		Source:     "test",
+		CustomerID: "CUST-TEST-CLOSED",
+		Type:       "withdrawal",
+	}
+	close(txChan)
+	<-done
+
+	if ledger.CustomerBalance != 1000 || ledger.BankBalance != 5000 {
+		t.Fatalf("ledger = %+v, want unchanged (closed-account transaction must be rejected)", ledger)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/LuoL23125/INFO5101-Lab9/pkg/racetest"
 )
 
 // Global variables for different tests
@@ -61,6 +63,13 @@ func testRaceCondition() {
 // ============================================
 func transactionProcessor(ledger *Ledger, txChan chan Transaction) {
 	for tx := range txChan {
+		if acct, ok := lookupAccount(tx.CustomerID); ok {
+			if _, balanceOK := acct.Balance(); !balanceOK {
+				fmt.Printf("⛔ Transaction rejected: account %s is closed\n", tx.CustomerID)
+				continue
+			}
+		}
+
 		// Display transaction type clearly
 		if tx.Type == "deposit" {
 			fmt.Printf("📥 Processing DEPOSIT: %s depositing %d for customer %s\n",
@@ -151,7 +160,22 @@ func testChannelBased() {
 			Type:       "deposit",
 		}
 	}()
-	
+
+	// BONUS: Transaction against a closed account - transactionProcessor
+	// must reject it without touching the ledger
+	closedAcct := Open(100)
+	registerAccount("CUST-CLOSED", closedAcct)
+	closedAcct.Close()
+	go func() {
+		time.Sleep(time.Millisecond * 250) // after the other sends
+		txChan <- Transaction{
+			Amount:     50,
+			Source:     "Late Withdrawal",
+			CustomerID: "CUST-CLOSED",
+			Type:       "withdrawal",
+		}
+	}()
+
 	// Give goroutines time to send transactions
 	time.Sleep(time.Second * 3)
 	
@@ -233,6 +257,17 @@ func testMutex() {
 	wg.Wait()
 	
 	fmt.Printf("\nFinal balance: %d ✅ CORRECT!\n", balance)
+
+	// BONUS: Closed accounts short-circuit instead of mutating balances
+	fmt.Println("\n--- Closed account guard (BONUS) ---")
+	acct := Open(250)
+	registerAccount("CUST-CLOSED", acct)
+	if payout, ok := acct.Close(); ok {
+		fmt.Printf("Closed CUST-CLOSED, paid out %d\n", payout)
+	}
+	if _, ok := acct.Withdraw(10); !ok {
+		fmt.Println("Withdraw against closed account correctly rejected")
+	}
 }
 
 // ============================================
@@ -280,6 +315,29 @@ func testComparison() {
 	fmt.Println("  • Single processor handles all transactions sequentially")
 	fmt.Println("  • Natural serialization prevents race conditions")
 	fmt.Println("  • Implements proper double-entry bookkeeping")
+
+	// BONUS: Replace the ad-hoc "5 runs" loop above with a seeded
+	// comparison across implementations.
+	fmt.Println("\n🔬 Reproducible comparison (pkg/racetest, 50 fuzzed runs each):")
+	harness := racetest.Harness{
+		Impls:   []racetest.Implementation{racetest.MutexImpl{}, racetest.ChannelImpl{}, racetest.AtomicImpl{}},
+		Initial: racetest.LedgerState{Customer: 1000, Bank: 5000},
+		Txs: []racetest.Transaction{
+			{Amount: 700, Type: "withdrawal"},
+			{Amount: 500, Type: "withdrawal"},
+			{Amount: 400, Type: "withdrawal"},
+			{Amount: 1500, Type: "deposit"},
+		},
+		Runs: 50,
+		Seed: 42,
+	}
+	results := harness.Execute()
+
+	var stats []racetest.Stats
+	for _, impl := range harness.Impls {
+		stats = append(stats, racetest.Summarize(impl.Name(), results[impl.Name()], len(harness.Txs)))
+	}
+	fmt.Print(racetest.FormatTable(stats))
 }
 
 // ============================================
@@ -295,7 +353,15 @@ func main() {
 	testChannelBased()      // Part 2: Channel solution
 	testMutex()            // Part 3: Mutex solution
 	testComparison()       // Bonus: Comparison
-	
+
+	// BONUS: External deposits flowing into the existing channel pipeline
+	ledger := Ledger{CustomerBalance: 1000, BankBalance: 5000}
+	depositTxChan := make(chan Transaction)
+	go transactionProcessor(&ledger, depositTxChan)
+	runDepositWatcherDemo(depositTxChan)
+	close(depositTxChan)
+	time.Sleep(time.Millisecond * 100)
+
 	fmt.Println("\n=====================================")
 	fmt.Println("         ALL TESTS COMPLETE")
 	fmt.Println("=====================================")
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/LuoL23125/INFO5101-Lab9/pkg/depositwatcher"
+)
+
+// fakeDepositSource is a DepositSource used by the demo below to stand in
+// for a real wire/ACH/on-chain feed; depositwatcher.DepositSource is the
+// hook a test would use to inject the same kind of fake.
+type fakeDepositSource struct {
+	deposits []depositwatcher.ExternalDeposit
+}
+
+func (f *fakeDepositSource) Poll(since map[string]time.Time) ([]depositwatcher.ExternalDeposit, error) {
+	return f.deposits, nil
+}
+
+// runDepositWatcherDemo polls a fake external deposit source and feeds every
+// confirmed deposit into txChan as a Transaction, the same channel
+// transactionProcessor already reads from. This is the bridge the
+// depositwatcher package needs to actually affect a ledger instead of
+// just observing deposits in isolation.
+func runDepositWatcherDemo(txChan chan Transaction) {
+	fmt.Println("\n========================================")
+	fmt.Println("BONUS: DEPOSIT WATCHER (external deposits)")
+	fmt.Println("========================================")
+
+	seenFile, err := os.CreateTemp("", "deposit-watcher-seen-*.txt")
+	if err != nil {
+		fmt.Printf("deposit watcher: create seen file: %v\n", err)
+		return
+	}
+	seenFile.Close()
+	defer os.Remove(seenFile.Name())
+
+	seen, err := depositwatcher.OpenFileSeenStore(seenFile.Name())
+	if err != nil {
+		fmt.Printf("deposit watcher: open seen store: %v\n", err)
+		return
+	}
+	defer seen.Close()
+
+	source := &fakeDepositSource{
+		deposits: []depositwatcher.ExternalDeposit{
+			{RefID: "wire-001", Asset: "USD", CustomerID: "CUST1001", Amount: 900, Confirmations: 3, ObservedAt: time.Now()},
+		},
+	}
+
+	submit := func(ev depositwatcher.DepositEvent) {
+		fmt.Printf("📨 External deposit confirmed: %s for %s, amount %d\n", ev.RefID, ev.CustomerID, ev.Amount)
+		txChan <- Transaction{
+			Amount:     ev.Amount,
+			Source:     "External Deposit " + ev.RefID,
+			CustomerID: ev.CustomerID,
+			Type:       "deposit",
+		}
+	}
+
+	watcher := depositwatcher.NewWatcher(source, seen, submit, 2, 100*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 350*time.Millisecond)
+	defer cancel()
+	if err := watcher.Run(ctx); err != nil && err != context.DeadlineExceeded {
+		fmt.Printf("deposit watcher: %v\n", err)
+	}
+}
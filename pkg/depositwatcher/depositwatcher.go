@@ -0,0 +1,137 @@
+// Package depositwatcher polls external deposit sources (wire, ACH, on-chain
+// confirmations) and turns a confirmed deposit into a submission the ledger
+// can apply, bridging "money arrived from outside" into the existing
+// transaction pipeline.
+package depositwatcher
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// ExternalDeposit is one observation returned by a DepositSource. The same
+// RefID may be reported repeatedly by Poll as its Confirmations count rises;
+// the Watcher only acts once Confirmations reaches its configured
+// threshold.
+type ExternalDeposit struct {
+	RefID         string
+	Asset         string
+	CustomerID    string
+	Amount        int
+	Confirmations int
+	ObservedAt    time.Time
+}
+
+// DepositSource is the pluggable external feed. since holds the last
+// observed deposit time per asset, letting a real implementation avoid
+// re-scanning history it has already reported.
+type DepositSource interface {
+	Poll(since map[string]time.Time) ([]ExternalDeposit, error)
+}
+
+// DepositEvent is what the Watcher hands to Submit once a deposit has
+// cleared its confirmation threshold. It intentionally doesn't reuse the
+// main package's Transaction type: this package can't import package main,
+// so callers adapt DepositEvent into whatever transaction type their
+// pipeline expects.
+type DepositEvent struct {
+	RefID      string
+	CustomerID string
+	Amount     int
+}
+
+// Watcher periodically polls a DepositSource and submits each deposit that
+// has reached Threshold confirmations, exactly once per RefID.
+type Watcher struct {
+	Source    DepositSource
+	Seen      SeenStore
+	Submit    func(DepositEvent)
+	Threshold int
+	Interval  time.Duration
+	Limiter   *rate.Limiter
+
+	mu                    sync.Mutex
+	lastAssetDepositTimes map[string]time.Time
+}
+
+// NewWatcher wires up a Watcher. Threshold is the minimum confirmation
+// count before a deposit is submitted; interval is how often to poll, and
+// also the default rate limit applied to polling (one poll per interval,
+// with a burst of one).
+func NewWatcher(source DepositSource, seen SeenStore, submit func(DepositEvent), threshold int, interval time.Duration) *Watcher {
+	return &Watcher{
+		Source:                source,
+		Seen:                  seen,
+		Submit:                submit,
+		Threshold:             threshold,
+		Interval:              interval,
+		Limiter:               rate.NewLimiter(rate.Every(interval), 1),
+		lastAssetDepositTimes: make(map[string]time.Time),
+	}
+}
+
+// Run polls on Interval until ctx is cancelled or a poll fails, returning
+// ctx.Err() or the wrapped poll error.
+func (w *Watcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := w.pollOnce(ctx); err != nil {
+				return fmt.Errorf("depositwatcher: poll: %w", err)
+			}
+		}
+	}
+}
+
+func (w *Watcher) pollOnce(ctx context.Context) error {
+	if w.Limiter != nil {
+		if err := w.Limiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+
+	w.mu.Lock()
+	since := make(map[string]time.Time, len(w.lastAssetDepositTimes))
+	for asset, t := range w.lastAssetDepositTimes {
+		since[asset] = t
+	}
+	w.mu.Unlock()
+
+	deposits, err := w.Source.Poll(since)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range deposits {
+		if d.Confirmations < w.Threshold {
+			continue
+		}
+		seen, err := w.Seen.Seen(d.RefID)
+		if err != nil {
+			return fmt.Errorf("depositwatcher: check seen %s: %w", d.RefID, err)
+		}
+		if seen {
+			continue
+		}
+		if err := w.Seen.MarkSeen(d.RefID); err != nil {
+			return fmt.Errorf("depositwatcher: mark seen %s: %w", d.RefID, err)
+		}
+
+		w.mu.Lock()
+		if d.ObservedAt.After(w.lastAssetDepositTimes[d.Asset]) {
+			w.lastAssetDepositTimes[d.Asset] = d.ObservedAt
+		}
+		w.mu.Unlock()
+
+		w.Submit(DepositEvent{RefID: d.RefID, CustomerID: d.CustomerID, Amount: d.Amount})
+	}
+	return nil
+}
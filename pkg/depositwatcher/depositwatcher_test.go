@@ -0,0 +1,52 @@
+package depositwatcher
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeDepositSource is the kind of fake a test injects via DepositSource to
+// exercise Watcher without a real wire/ACH/on-chain feed.
+type fakeDepositSource struct {
+	deposits []ExternalDeposit
+}
+
+func (f *fakeDepositSource) Poll(since map[string]time.Time) ([]ExternalDeposit, error) {
+	return f.deposits, nil
+}
+
+func TestWatcherSubmitsConfirmedDepositsOnce(t *testing.T) {
+	seen, err := OpenFileSeenStore(filepath.Join(t.TempDir(), "seen.txt"))
+	if err != nil {
+		t.Fatalf("OpenFileSeenStore: %v", err)
+	}
+	defer seen.Close()
+
+	source := &fakeDepositSource{
+		deposits: []ExternalDeposit{
+			{RefID: "wire-1", Asset: "USD", CustomerID: "cust-1", Amount: 100, Confirmations: 3},
+			{RefID: "wire-2", Asset: "USD", CustomerID: "cust-2", Amount: 50, Confirmations: 1}, // below threshold
+		},
+	}
+
+	var submitted []DepositEvent
+	w := NewWatcher(source, seen, func(ev DepositEvent) {
+		submitted = append(submitted, ev)
+	}, 2, 10*time.Millisecond)
+
+	if err := w.pollOnce(context.Background()); err != nil {
+		t.Fatalf("pollOnce: %v", err)
+	}
+	if err := w.pollOnce(context.Background()); err != nil {
+		t.Fatalf("second pollOnce: %v", err)
+	}
+
+	if len(submitted) != 1 {
+		t.Fatalf("submitted = %+v, want exactly one deposit", submitted)
+	}
+	if submitted[0].RefID != "wire-1" {
+		t.Fatalf("submitted RefID = %s, want wire-1", submitted[0].RefID)
+	}
+}
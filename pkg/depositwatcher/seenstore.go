@@ -0,0 +1,72 @@
+package depositwatcher
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// SeenStore deduplicates deposits by external reference ID across process
+// restarts.
+type SeenStore interface {
+	Seen(refID string) (bool, error)
+	MarkSeen(refID string) error
+}
+
+// FileSeenStore persists the seen-set as one reference ID per line in an
+// append-only file, loaded into memory on open.
+type FileSeenStore struct {
+	mu   sync.Mutex
+	path string
+	seen map[string]struct{}
+	file *os.File
+}
+
+// OpenFileSeenStore opens (creating if necessary) the seen-set file at
+// path and loads its existing contents.
+func OpenFileSeenStore(path string) (*FileSeenStore, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("depositwatcher: open seen store: %w", err)
+	}
+
+	seen := make(map[string]struct{})
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			seen[line] = struct{}{}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("depositwatcher: read seen store: %w", err)
+	}
+
+	return &FileSeenStore{path: path, seen: seen, file: f}, nil
+}
+
+func (s *FileSeenStore) Seen(refID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.seen[refID]
+	return ok, nil
+}
+
+func (s *FileSeenStore) MarkSeen(refID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.seen[refID]; ok {
+		return nil
+	}
+	if _, err := fmt.Fprintln(s.file, refID); err != nil {
+		return fmt.Errorf("depositwatcher: write seen store: %w", err)
+	}
+	s.seen[refID] = struct{}{}
+	return nil
+}
+
+// Close releases the underlying file handle.
+func (s *FileSeenStore) Close() error {
+	return s.file.Close()
+}
@@ -0,0 +1,187 @@
+// Package ledger implements a multi-account ledger with double-entry
+// journal postings. Every Deposit, Withdraw, and Transfer produces a pair
+// of matching debit/credit entries, so sum(all balances) ==
+// sum(all deposits) - sum(all withdrawals) holds as an invariant that
+// Verify can check.
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Typed errors distinguish business errors (the caller should show these to
+// a user) from anything else (infrastructure problems a caller should
+// retry or alert on).
+var (
+	ErrNoMoney         = errors.New("ledger: insufficient funds")
+	ErrAccountExists   = errors.New("ledger: account already exists")
+	ErrAccountNotFound = errors.New("ledger: account not found")
+)
+
+// EntryType identifies which side of a posting a JournalEntry represents.
+type EntryType string
+
+const (
+	EntryDebit  EntryType = "debit"
+	EntryCredit EntryType = "credit"
+)
+
+// JournalEntry is one leg of an immutable, append-only posting. A Deposit
+// or Withdraw produces two entries (account vs. the implicit external
+// source); a Transfer produces a debit on From and a credit on To.
+type JournalEntry struct {
+	Account   string
+	Type      EntryType
+	Amount    int64
+	Reason    string
+	Timestamp time.Time
+}
+
+// Store is the pluggable backend behind a Ledger. Implementations must
+// execute Apply atomically: either every entry in the batch is applied and
+// every balance updated, or none of them are.
+type Store interface {
+	CreateAccount(id string) error
+	Balance(id string) (int64, error)
+	// Apply atomically adjusts each account in deltas by its signed amount
+	// and appends entries to the journal. It must return ErrNoMoney, without
+	// applying any of the deltas, if a negative delta would take its
+	// account below zero.
+	Apply(deltas map[string]int64, entries []JournalEntry) error
+	Journal() ([]JournalEntry, error)
+	Accounts() ([]string, error)
+}
+
+// Ledger is the public API: account lifecycle plus money movement, all
+// backed by a Store.
+type Ledger struct {
+	store Store
+}
+
+// New wraps store in a Ledger.
+func New(store Store) *Ledger {
+	return &Ledger{store: store}
+}
+
+// CreateAccount opens a new, zero-balance account. It returns
+// ErrAccountExists if id is already in use.
+func (l *Ledger) CreateAccount(id string) error {
+	return l.store.CreateAccount(id)
+}
+
+// GetBalance returns the current balance of id, or ErrAccountNotFound.
+func (l *Ledger) GetBalance(id string) (int64, error) {
+	return l.store.Balance(id)
+}
+
+// Deposit credits amount into id, journaling a matching credit/debit pair.
+func (l *Ledger) Deposit(id string, amount int64) error {
+	if _, err := l.store.Balance(id); err != nil {
+		return err
+	}
+	now := time.Now()
+	entries := []JournalEntry{
+		{Account: id, Type: EntryCredit, Amount: amount, Reason: "deposit", Timestamp: now},
+		{Account: externalAccount, Type: EntryDebit, Amount: amount, Reason: "deposit", Timestamp: now},
+	}
+	return l.store.Apply(map[string]int64{id: amount}, entries)
+}
+
+// Withdraw debits amount from id, returning ErrNoMoney if the balance is
+// insufficient.
+func (l *Ledger) Withdraw(id string, amount int64) error {
+	if _, err := l.store.Balance(id); err != nil {
+		return err
+	}
+	now := time.Now()
+	entries := []JournalEntry{
+		{Account: id, Type: EntryDebit, Amount: amount, Reason: "withdraw", Timestamp: now},
+		{Account: externalAccount, Type: EntryCredit, Amount: amount, Reason: "withdraw", Timestamp: now},
+	}
+	return l.store.Apply(map[string]int64{id: -amount}, entries)
+}
+
+// Transfer moves amount from from to to as a single atomic posting. Callers
+// passing arbitrary account pairs concurrently are safe: Store
+// implementations must lock accounts in sorted ID order to stay
+// deadlock-free.
+func (l *Ledger) Transfer(from, to string, amount int64) error {
+	if _, err := l.store.Balance(from); err != nil {
+		return err
+	}
+	if _, err := l.store.Balance(to); err != nil {
+		return err
+	}
+	now := time.Now()
+	entries := []JournalEntry{
+		{Account: from, Type: EntryDebit, Amount: amount, Reason: "transfer", Timestamp: now},
+		{Account: to, Type: EntryCredit, Amount: amount, Reason: "transfer", Timestamp: now},
+	}
+	return l.store.Apply(map[string]int64{from: -amount, to: amount}, entries)
+}
+
+// Verify recomputes every balance from the journal and confirms it matches
+// what the store reports, and that total deposits minus total withdrawals
+// equals the sum of all account balances. It returns an error describing
+// the first mismatch found.
+func (l *Ledger) Verify() error {
+	entries, err := l.store.Journal()
+	if err != nil {
+		return err
+	}
+	accounts, err := l.store.Accounts()
+	if err != nil {
+		return err
+	}
+
+	computed := make(map[string]int64, len(accounts))
+	for _, id := range accounts {
+		computed[id] = 0
+	}
+	var deposits, withdrawals int64
+	for _, e := range entries {
+		switch e.Reason {
+		case "deposit":
+			if e.Type == EntryCredit {
+				deposits += e.Amount
+			}
+		case "withdraw":
+			if e.Type == EntryDebit {
+				withdrawals += e.Amount
+			}
+		}
+		if _, ok := computed[e.Account]; !ok {
+			continue // external side of a deposit/withdraw posting
+		}
+		if e.Type == EntryCredit {
+			computed[e.Account] += e.Amount
+		} else {
+			computed[e.Account] -= e.Amount
+		}
+	}
+
+	sort.Strings(accounts)
+	var total int64
+	for _, id := range accounts {
+		balance, err := l.store.Balance(id)
+		if err != nil {
+			return err
+		}
+		if computed[id] != balance {
+			return fmt.Errorf("ledger: account %s balance %d does not match journal total %d", id, balance, computed[id])
+		}
+		total += balance
+	}
+	if total != deposits-withdrawals {
+		return fmt.Errorf("ledger: total balance %d does not equal deposits-withdrawals %d", total, deposits-withdrawals)
+	}
+	return nil
+}
+
+// externalAccount is the implicit counterparty for deposits and
+// withdrawals, which move money across the boundary of the ledger rather
+// than between two accounts it tracks.
+const externalAccount = "__external__"
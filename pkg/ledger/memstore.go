@@ -0,0 +1,88 @@
+package ledger
+
+import (
+	"sort"
+	"sync"
+)
+
+// MemoryStore is an in-memory Store. A single mutex guards every account,
+// which keeps Apply trivially deadlock-free; Apply still sorts the affected
+// account IDs before touching them so the locking discipline matches what a
+// SQL-backed Store (locking rows in sorted ID order to avoid deadlocking
+// against a concurrent transfer the other direction) would need to do.
+type MemoryStore struct {
+	mu       sync.Mutex
+	balances map[string]int64
+	journal  []JournalEntry
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{balances: make(map[string]int64)}
+}
+
+func (s *MemoryStore) CreateAccount(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.balances[id]; ok {
+		return ErrAccountExists
+	}
+	s.balances[id] = 0
+	return nil
+}
+
+func (s *MemoryStore) Balance(id string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	balance, ok := s.balances[id]
+	if !ok {
+		return 0, ErrAccountNotFound
+	}
+	return balance, nil
+}
+
+func (s *MemoryStore) Apply(deltas map[string]int64, entries []JournalEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]string, 0, len(deltas))
+	for id := range deltas {
+		if id == externalAccount {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if _, ok := s.balances[id]; !ok {
+			return ErrAccountNotFound
+		}
+		if next := s.balances[id] + deltas[id]; next < 0 {
+			return ErrNoMoney
+		}
+	}
+	for _, id := range ids {
+		s.balances[id] += deltas[id]
+	}
+	s.journal = append(s.journal, entries...)
+	return nil
+}
+
+func (s *MemoryStore) Journal() ([]JournalEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]JournalEntry, len(s.journal))
+	copy(out, s.journal)
+	return out, nil
+}
+
+func (s *MemoryStore) Accounts() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]string, 0, len(s.balances))
+	for id := range s.balances {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
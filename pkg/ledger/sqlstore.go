@@ -0,0 +1,218 @@
+package ledger
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Dialect picks the placeholder syntax and row-locking strategy SQLStore
+// uses, since Postgres and SQLite disagree on both.
+type Dialect int
+
+const (
+	// DialectPostgres uses $N placeholders and locks rows with
+	// SELECT ... FOR UPDATE inside a SERIALIZABLE transaction.
+	DialectPostgres Dialect = iota
+	// DialectSQLite uses ? placeholders. SQLite has no row-level locking
+	// clause, so instead of FOR UPDATE it relies on the whole-database
+	// write lock SQLite takes at the start of an immediate transaction;
+	// the caller must open the database with "_txlock=immediate" in the
+	// DSN (e.g. "file:ledger.db?_txlock=immediate") for that lock to be
+	// taken at BeginTx instead of at the first write, which is what
+	// actually closes the read-then-write race Apply depends on.
+	DialectSQLite
+)
+
+// SQLStore is a Store backed by any database/sql driver that supports
+// Dialect's transaction semantics (Postgres, or SQLite opened with
+// "_txlock=immediate"). The caller is responsible for sql.Open-ing db and
+// creating the "ledger_accounts" (id TEXT PRIMARY KEY, balance BIGINT) and
+// "ledger_journal" (account TEXT, type TEXT, amount BIGINT, reason TEXT,
+// at TIMESTAMP) tables; SQLStore only issues statements against them.
+type SQLStore struct {
+	DB      *sql.DB
+	Dialect Dialect
+	// MaxRetries bounds how many times Apply retries after the driver
+	// reports a serialization failure. Defaults to 5 if zero.
+	MaxRetries int
+}
+
+// NewSQLStore wraps db, an already-opened connection to a database whose
+// ledger_accounts / ledger_journal tables already exist.
+func NewSQLStore(db *sql.DB, dialect Dialect) *SQLStore {
+	return &SQLStore{DB: db, Dialect: dialect}
+}
+
+// ph returns the n-th (1-based) placeholder for the store's dialect.
+func (s *SQLStore) ph(n int) string {
+	if s.Dialect == DialectSQLite {
+		return "?"
+	}
+	return fmt.Sprintf("$%d", n)
+}
+
+func (s *SQLStore) CreateAccount(id string) error {
+	query := fmt.Sprintf(`INSERT INTO ledger_accounts (id, balance) VALUES (%s, 0)`, s.ph(1))
+	_, err := s.DB.Exec(query, id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrAccountExists
+		}
+		return fmt.Errorf("ledger: create account %s: %w", id, err)
+	}
+	return nil
+}
+
+func (s *SQLStore) Balance(id string) (int64, error) {
+	var balance int64
+	query := fmt.Sprintf(`SELECT balance FROM ledger_accounts WHERE id = %s`, s.ph(1))
+	err := s.DB.QueryRow(query, id).Scan(&balance)
+	if err == sql.ErrNoRows {
+		return 0, ErrAccountNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("ledger: balance %s: %w", id, err)
+	}
+	return balance, nil
+}
+
+// Apply runs the adjustment inside a SERIALIZABLE transaction, retrying
+// automatically if the driver reports a serialization failure (two
+// concurrent transfers touching overlapping accounts). Account IDs are
+// locked in sorted order within the transaction to stay deadlock-free
+// against a concurrent transfer running the other direction.
+func (s *SQLStore) Apply(deltas map[string]int64, entries []JournalEntry) error {
+	ids := make([]string, 0, len(deltas))
+	for id := range deltas {
+		if id != externalAccount {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	maxRetries := s.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = 5
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		err := s.applyOnce(ids, deltas, entries)
+		if err == nil {
+			return nil
+		}
+		if !isSerializationFailure(err) {
+			return err
+		}
+		lastErr = err
+		time.Sleep(time.Duration(attempt+1) * 10 * time.Millisecond)
+	}
+	return fmt.Errorf("ledger: apply: exhausted retries: %w", lastErr)
+}
+
+func (s *SQLStore) applyOnce(ids []string, deltas map[string]int64, entries []JournalEntry) error {
+	opts := &sql.TxOptions{}
+	if s.Dialect == DialectPostgres {
+		opts.Isolation = sql.LevelSerializable
+	}
+	tx, err := s.DB.BeginTx(context.Background(), opts)
+	if err != nil {
+		return fmt.Errorf("ledger: begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	selectQuery := fmt.Sprintf(`SELECT balance FROM ledger_accounts WHERE id = %s`, s.ph(1))
+	if s.Dialect == DialectPostgres {
+		selectQuery += ` FOR UPDATE`
+	}
+	for _, id := range ids {
+		var balance int64
+		if err := tx.QueryRow(selectQuery, id).Scan(&balance); err != nil {
+			if err == sql.ErrNoRows {
+				return ErrAccountNotFound
+			}
+			return fmt.Errorf("ledger: lock %s: %w", id, err)
+		}
+		if next := balance + deltas[id]; next < 0 {
+			return ErrNoMoney
+		}
+	}
+
+	updateQuery := fmt.Sprintf(`UPDATE ledger_accounts SET balance = balance + %s WHERE id = %s`, s.ph(1), s.ph(2))
+	for _, id := range ids {
+		if _, err := tx.Exec(updateQuery, deltas[id], id); err != nil {
+			return fmt.Errorf("ledger: update %s: %w", id, err)
+		}
+	}
+
+	insertQuery := fmt.Sprintf(
+		`INSERT INTO ledger_journal (account, type, amount, reason, at) VALUES (%s, %s, %s, %s, %s)`,
+		s.ph(1), s.ph(2), s.ph(3), s.ph(4), s.ph(5),
+	)
+	for _, e := range entries {
+		if _, err := tx.Exec(insertQuery, e.Account, string(e.Type), e.Amount, e.Reason, e.Timestamp); err != nil {
+			return fmt.Errorf("ledger: journal insert: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ledger: commit: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLStore) Journal() ([]JournalEntry, error) {
+	rows, err := s.DB.Query(`SELECT account, type, amount, reason, at FROM ledger_journal ORDER BY at`)
+	if err != nil {
+		return nil, fmt.Errorf("ledger: journal: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []JournalEntry
+	for rows.Next() {
+		var e JournalEntry
+		var entryType string
+		if err := rows.Scan(&e.Account, &entryType, &e.Amount, &e.Reason, &e.Timestamp); err != nil {
+			return nil, fmt.Errorf("ledger: journal scan: %w", err)
+		}
+		e.Type = EntryType(entryType)
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func (s *SQLStore) Accounts() ([]string, error) {
+	rows, err := s.DB.Query(`SELECT id FROM ledger_accounts`)
+	if err != nil {
+		return nil, fmt.Errorf("ledger: accounts: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("ledger: accounts scan: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// isSerializationFailure matches the Postgres and SQLite error text for a
+// transaction that lost a serialization race, without depending on either
+// driver's package (this module doesn't vendor one) to check a typed error
+// code.
+func isSerializationFailure(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "serialization") || strings.Contains(msg, "could not serialize") ||
+		strings.Contains(msg, "database is locked") || strings.Contains(msg, "deadlock")
+}
+
+func isUniqueViolation(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "unique") || strings.Contains(msg, "duplicate")
+}
@@ -0,0 +1,108 @@
+package racetest
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// MutexImpl applies every Transaction under a single sync.Mutex, the same
+// strategy as bank.go's testMutex.
+type MutexImpl struct{}
+
+func (MutexImpl) Name() string { return "mutex" }
+
+func (MutexImpl) Run(sched *Scheduler, initial LedgerState, txs []Transaction) (LedgerState, error) {
+	state := initial
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	wg.Add(len(txs))
+	for _, tx := range txs {
+		go func(tx Transaction) {
+			defer wg.Done()
+			mu.Lock()
+			defer mu.Unlock()
+			sched.MaybeYield()
+			applyLocked(&state, tx)
+		}(tx)
+	}
+	wg.Wait()
+	return state, nil
+}
+
+// ChannelImpl serializes every Transaction through a single processor
+// goroutine, the same strategy as bank.go's transactionProcessor.
+type ChannelImpl struct{}
+
+func (ChannelImpl) Name() string { return "channel" }
+
+func (ChannelImpl) Run(sched *Scheduler, initial LedgerState, txs []Transaction) (LedgerState, error) {
+	state := initial
+	txChan := make(chan Transaction)
+	done := make(chan struct{})
+
+	go func() {
+		for tx := range txChan {
+			sched.MaybeYield()
+			applyLocked(&state, tx)
+		}
+		close(done)
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(len(txs))
+	for _, tx := range txs {
+		go func(tx Transaction) {
+			defer wg.Done()
+			txChan <- tx
+		}(tx)
+	}
+	wg.Wait()
+	close(txChan)
+	<-done
+	return state, nil
+}
+
+// AtomicImpl applies every Transaction lock-free using atomic.AddInt64,
+// only valid for deposits and withdrawals that don't need to check the
+// balance first (it never rejects an insufficient-funds withdrawal, so
+// running it against a workload that depends on that check will itself
+// surface as an invariant violation).
+type AtomicImpl struct{}
+
+func (AtomicImpl) Name() string { return "atomic" }
+
+func (AtomicImpl) Run(sched *Scheduler, initial LedgerState, txs []Transaction) (LedgerState, error) {
+	customer := int64(initial.Customer)
+	bank := int64(initial.Bank)
+	var wg sync.WaitGroup
+	wg.Add(len(txs))
+	for _, tx := range txs {
+		go func(tx Transaction) {
+			defer wg.Done()
+			sched.MaybeYield()
+			switch tx.Type {
+			case "withdrawal":
+				atomic.AddInt64(&customer, -int64(tx.Amount))
+				atomic.AddInt64(&bank, int64(tx.Amount))
+			case "deposit":
+				atomic.AddInt64(&customer, int64(tx.Amount))
+				atomic.AddInt64(&bank, -int64(tx.Amount))
+			}
+		}(tx)
+	}
+	wg.Wait()
+	return LedgerState{Customer: int(atomic.LoadInt64(&customer)), Bank: int(atomic.LoadInt64(&bank))}, nil
+}
+
+func applyLocked(state *LedgerState, tx Transaction) {
+	switch tx.Type {
+	case "withdrawal":
+		if state.Customer >= tx.Amount {
+			state.Customer -= tx.Amount
+			state.Bank += tx.Amount
+		}
+	case "deposit":
+		state.Customer += tx.Amount
+		state.Bank -= tx.Amount
+	}
+}
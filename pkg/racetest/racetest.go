@@ -0,0 +1,125 @@
+// Package racetest grows the "run it five times and eyeball the output"
+// comparison into a seeded harness: it drives a workload of
+// Transactions through one or more concurrency Implementations under
+// randomized goroutine interleavings and reports the distribution of final
+// ledger states plus any invariant violations.
+package racetest
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Transaction is a withdrawal or deposit request for the harness workload.
+type Transaction struct {
+	Amount int
+	Type   string // "withdrawal" or "deposit"
+}
+
+// LedgerState is the two-account balance an Implementation starts from and
+// ends at, mirroring the customer/bank split used elsewhere in this
+// project's demos.
+type LedgerState struct {
+	Customer int
+	Bank     int
+}
+
+// Scheduler hands an Implementation a seeded source of randomness plus a
+// cooperative-yield injection point. The same seed reproduces the same
+// sequence of yield decisions, but which goroutine draws each decision is
+// up to the Go scheduler, so the interleavings and final states of a run
+// are not reproduced exactly. MutexImpl and AtomicImpl call MaybeYield from
+// every transaction's own goroutine, so access to the underlying *rand.Rand
+// (which is not itself safe for concurrent use) is serialized by mu.
+type Scheduler struct {
+	mu     sync.Mutex
+	rng    *rand.Rand
+	pYield float64
+}
+
+// NewScheduler seeds a Scheduler; pYield is the probability MaybeYield
+// actually yields, in [0,1].
+func NewScheduler(seed int64, pYield float64) *Scheduler {
+	return &Scheduler{rng: rand.New(rand.NewSource(seed)), pYield: pYield}
+}
+
+// MaybeYield calls runtime.Gosched with probability pYield. Implementations
+// should call this between the "check" and "act" halves of an operation to
+// widen the window a race can land in.
+func (s *Scheduler) MaybeYield() {
+	s.mu.Lock()
+	yield := s.rng.Float64() < s.pYield
+	s.mu.Unlock()
+	if yield {
+		runtime.Gosched()
+	}
+}
+
+// Implementation is one concurrency strategy under test (mutex-guarded,
+// channel-serialized, atomic/lock-free, ...).
+type Implementation interface {
+	Name() string
+	// Run applies txs concurrently against a ledger starting at initial,
+	// using sched for its interleaving fuzz, and returns the final state.
+	Run(sched *Scheduler, initial LedgerState, txs []Transaction) (LedgerState, error)
+}
+
+// RunResult is the outcome of a single fuzzed run.
+type RunResult struct {
+	Final      LedgerState
+	Violations []string
+	Duration   time.Duration
+}
+
+// Harness runs Txs against Initial through every Impl, Runs times each,
+// with a distinct seed per run derived from Seed.
+type Harness struct {
+	Impls   []Implementation
+	Initial LedgerState
+	Txs     []Transaction
+	Runs    int
+	Seed    int64
+	// PYield is the per-yield-point probability passed to each run's
+	// Scheduler. Defaults to 0.5 if zero.
+	PYield float64
+}
+
+// Execute runs the harness and returns every RunResult, keyed by
+// Implementation name.
+func (h *Harness) Execute() map[string][]RunResult {
+	pYield := h.PYield
+	if pYield == 0 {
+		pYield = 0.5
+	}
+
+	results := make(map[string][]RunResult, len(h.Impls))
+	initialTotal := h.Initial.Customer + h.Initial.Bank
+
+	for _, impl := range h.Impls {
+		runs := make([]RunResult, 0, h.Runs)
+		for i := 0; i < h.Runs; i++ {
+			sched := NewScheduler(h.Seed+int64(i), pYield)
+			start := time.Now()
+			final, err := impl.Run(sched, h.Initial, h.Txs)
+			duration := time.Since(start)
+
+			var violations []string
+			if err != nil {
+				violations = append(violations, fmt.Sprintf("implementation error: %v", err))
+			}
+			if final.Customer < 0 || final.Bank < 0 {
+				violations = append(violations, fmt.Sprintf("negative balance: customer=%d bank=%d", final.Customer, final.Bank))
+			}
+			if final.Customer+final.Bank != initialTotal {
+				violations = append(violations, fmt.Sprintf("invariant violated: customer+bank=%d want %d", final.Customer+final.Bank, initialTotal))
+			}
+
+			runs = append(runs, RunResult{Final: final, Violations: violations, Duration: duration})
+		}
+		results[impl.Name()] = runs
+	}
+	return results
+}
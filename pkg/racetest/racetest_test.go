@@ -0,0 +1,71 @@
+package racetest
+
+import "testing"
+
+func TestHarnessReportsConsistentOutcomes(t *testing.T) {
+	h := Harness{
+		Impls:   []Implementation{MutexImpl{}, ChannelImpl{}},
+		Initial: LedgerState{Customer: 1000, Bank: 5000},
+		Txs: []Transaction{
+			{Amount: 700, Type: "withdrawal"},
+			{Amount: 500, Type: "withdrawal"},
+			{Amount: 1500, Type: "deposit"},
+		},
+		Runs: 20,
+		Seed: 7,
+	}
+
+	results := h.Execute()
+	for _, impl := range h.Impls {
+		runs, ok := results[impl.Name()]
+		if !ok || len(runs) != 20 {
+			t.Fatalf("%s: got %d runs, want 20", impl.Name(), len(runs))
+		}
+		for _, r := range runs {
+			if len(r.Violations) != 0 {
+				t.Errorf("%s: unexpected violation(s): %v", impl.Name(), r.Violations)
+			}
+		}
+	}
+}
+
+func TestAtomicImplCanViolateTheInvariantItSkipsChecking(t *testing.T) {
+	// AtomicImpl never checks sufficient funds before debiting, so a
+	// workload that overdraws the customer balance should surface as a
+	// negative-balance violation instead of silently under-reporting it.
+	h := Harness{
+		Impls:   []Implementation{AtomicImpl{}},
+		Initial: LedgerState{Customer: 100, Bank: 0},
+		Txs: []Transaction{
+			{Amount: 80, Type: "withdrawal"},
+			{Amount: 80, Type: "withdrawal"},
+		},
+		Runs: 10,
+		Seed: 1,
+	}
+
+	results := h.Execute()["atomic"]
+	found := false
+	for _, r := range results {
+		if len(r.Violations) != 0 {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("expected at least one run to report a negative-balance violation")
+	}
+}
+
+func TestSummarizeAndFormatTable(t *testing.T) {
+	results := []RunResult{
+		{Final: LedgerState{Customer: 100}},
+		{Final: LedgerState{Customer: 200}},
+	}
+	stats := Summarize("test-impl", results, 3)
+	if stats.Runs != 2 || stats.MinCustomer != 100 || stats.MaxCustomer != 200 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	if table := FormatTable([]Stats{stats}); table == "" {
+		t.Fatal("FormatTable returned empty output")
+	}
+}
@@ -0,0 +1,73 @@
+package racetest
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Stats summarizes one Implementation's RunResults: the spread of final
+// balances it produced, how often it violated an invariant, and its
+// throughput.
+type Stats struct {
+	Impl               string
+	Runs               int
+	MinCustomer        int
+	MaxCustomer        int
+	VarianceCustomer   float64
+	Violations         int
+	ThroughputTxPerSec float64
+}
+
+// Summarize computes Stats for one Implementation's results. txPerRun is
+// how many Transactions each run applied, used to compute throughput.
+func Summarize(impl string, results []RunResult, txPerRun int) Stats {
+	if len(results) == 0 {
+		return Stats{Impl: impl}
+	}
+
+	stats := Stats{
+		Impl:        impl,
+		Runs:        len(results),
+		MinCustomer: results[0].Final.Customer,
+		MaxCustomer: results[0].Final.Customer,
+	}
+
+	var sum, totalDuration float64
+	for _, r := range results {
+		c := float64(r.Final.Customer)
+		sum += c
+		if r.Final.Customer < stats.MinCustomer {
+			stats.MinCustomer = r.Final.Customer
+		}
+		if r.Final.Customer > stats.MaxCustomer {
+			stats.MaxCustomer = r.Final.Customer
+		}
+		stats.Violations += len(r.Violations)
+		totalDuration += r.Duration.Seconds()
+	}
+
+	mean := sum / float64(len(results))
+	var variance float64
+	for _, r := range results {
+		d := float64(r.Final.Customer) - mean
+		variance += d * d
+	}
+	stats.VarianceCustomer = variance / float64(len(results))
+
+	if totalDuration > 0 {
+		stats.ThroughputTxPerSec = float64(txPerRun*len(results)) / totalDuration
+	}
+	return stats
+}
+
+// FormatTable renders a fixed-width comparison table, one row per Stats.
+func FormatTable(stats []Stats) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-10s %6s %10s %10s %12s %10s %12s\n",
+		"impl", "runs", "min(cust)", "max(cust)", "var(cust)", "violations", "tx/sec")
+	for _, s := range stats {
+		fmt.Fprintf(&b, "%-10s %6d %10d %10d %12.2f %10d %12.1f\n",
+			s.Impl, s.Runs, s.MinCustomer, s.MaxCustomer, s.VarianceCustomer, s.Violations, s.ThroughputTxPerSec)
+	}
+	return b.String()
+}
@@ -0,0 +1,172 @@
+package workflow
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/LuoL23125/INFO5101-Lab9/pkg/ledger"
+)
+
+// RefStore durably records which ledger operation refs have already been
+// applied. A process can crash after LedgerAdapter's underlying Ledger call
+// succeeds but before the Engine checkpoints the resulting status
+// transition, so "has this ref already run" can't be derived from State
+// alone — it has to be recorded by the adapter itself, and that record has
+// to survive the restart that triggers the replay.
+type RefStore interface {
+	Seen(ref string) (bool, error)
+	MarkSeen(ref string) error
+}
+
+// MemoryRefStore keeps the applied-ref set in process memory. It satisfies
+// RefStore and is useful for tests and for demos that don't need to survive
+// a restart.
+type MemoryRefStore struct {
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+// NewMemoryRefStore returns an empty, ready-to-use MemoryRefStore.
+func NewMemoryRefStore() *MemoryRefStore {
+	return &MemoryRefStore{seen: make(map[string]bool)}
+}
+
+func (s *MemoryRefStore) Seen(ref string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.seen[ref], nil
+}
+
+func (s *MemoryRefStore) MarkSeen(ref string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seen[ref] = true
+	return nil
+}
+
+// FileRefStore persists the applied-ref set as one ref per line in an
+// append-only file, loaded into memory on open, so a fresh LedgerAdapter
+// started after a restart still knows which refs it already applied.
+type FileRefStore struct {
+	mu   sync.Mutex
+	seen map[string]bool
+	file *os.File
+}
+
+// OpenFileRefStore opens (creating if necessary) the applied-ref file at
+// path and loads its existing contents.
+func OpenFileRefStore(path string) (*FileRefStore, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("workflow: open ref store: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			seen[line] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("workflow: read ref store: %w", err)
+	}
+
+	return &FileRefStore{seen: seen, file: f}, nil
+}
+
+func (s *FileRefStore) Seen(ref string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.seen[ref], nil
+}
+
+func (s *FileRefStore) MarkSeen(ref string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.seen[ref] {
+		return nil
+	}
+	if _, err := fmt.Fprintln(s.file, ref); err != nil {
+		return fmt.Errorf("workflow: write ref store: %w", err)
+	}
+	s.seen[ref] = true
+	return nil
+}
+
+// Close releases the underlying file handle.
+func (s *FileRefStore) Close() error {
+	return s.file.Close()
+}
+
+// LedgerAdapter satisfies the Ledger interface on top of *ledger.Ledger.
+// ledger.Ledger itself has no notion of a reference ID — its
+// Deposit/Withdraw methods are not idempotent — so the adapter is where the
+// "replaying a step after a crash doesn't double-debit" guarantee actually
+// lives: it remembers which ref IDs it has already applied, via Refs, and
+// skips the underlying call on a replay. Refs must be a durable RefStore
+// (FileRefStore or equivalent) for that guarantee to hold across a process
+// restart; MemoryRefStore only holds it within a single process lifetime.
+type LedgerAdapter struct {
+	Ledger *ledger.Ledger
+	Refs   RefStore
+}
+
+// NewLedgerAdapter wraps l so it can be used as an Engine's Ledger,
+// deduplicating replayed ops against refs.
+func NewLedgerAdapter(l *ledger.Ledger, refs RefStore) *LedgerAdapter {
+	return &LedgerAdapter{Ledger: l, Refs: refs}
+}
+
+func (a *LedgerAdapter) Withdraw(ref, account string, amount int) error {
+	// Namespaced so a Withdraw and a Deposit sharing the same caller ref
+	// (the Engine passes tx.ID to both legs of a transfer) dedupe
+	// independently instead of the deposit leg being skipped as an
+	// apparent replay of the withdraw.
+	return a.once("withdraw:"+ref, func() error {
+		err := a.Ledger.Withdraw(account, int64(amount))
+		if err == ledger.ErrNoMoney {
+			return ErrInsufficientFunds
+		}
+		if err == ledger.ErrAccountNotFound {
+			return ErrAccountNotFound
+		}
+		return err
+	})
+}
+
+func (a *LedgerAdapter) Deposit(ref, account string, amount int) error {
+	return a.once("deposit:"+ref, func() error {
+		err := a.Ledger.Deposit(account, int64(amount))
+		if err == ledger.ErrAccountNotFound {
+			return ErrAccountNotFound
+		}
+		return err
+	})
+}
+
+// once runs fn only the first time it's called for a given ref; later calls
+// with the same ref (the replay of a crashed workflow step, even against a
+// brand new LedgerAdapter after a restart) are a no-op success, which is
+// what keeps a resumed Engine from double-debiting.
+func (a *LedgerAdapter) once(ref string, fn func() error) error {
+	seen, err := a.Refs.Seen(ref)
+	if err != nil {
+		return fmt.Errorf("workflow: ref store lookup %s: %w", ref, err)
+	}
+	if seen {
+		return nil
+	}
+
+	if err := fn(); err != nil {
+		return fmt.Errorf("workflow: ledger op %s: %w", ref, err)
+	}
+
+	if err := a.Refs.MarkSeen(ref); err != nil {
+		return fmt.Errorf("workflow: ref store mark %s: %w", ref, err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package workflow
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/LuoL23125/INFO5101-Lab9/pkg/ledger"
+)
+
+func newTestAdapterLedger(t *testing.T) *ledger.Ledger {
+	t.Helper()
+	store := ledger.NewMemoryStore()
+	l := ledger.New(store)
+	if err := l.CreateAccount("alice"); err != nil {
+		t.Fatalf("CreateAccount(alice): %v", err)
+	}
+	if err := l.CreateAccount("bob"); err != nil {
+		t.Fatalf("CreateAccount(bob): %v", err)
+	}
+	if err := l.Deposit("alice", 100); err != nil {
+		t.Fatalf("Deposit(alice): %v", err)
+	}
+	return l
+}
+
+// TestLedgerAdapterDedupesAcrossRestart is the scenario a MemoryRefStore
+// can't cover: the first process's LedgerAdapter applies a withdraw and
+// then dies before the Engine checkpoints the Withdrawing->Depositing
+// transition, so Resume() re-enters StatusWithdrawing and calls Withdraw
+// again against a brand new LedgerAdapter. The applied-ref record has to
+// have survived on disk for that replay to be a no-op instead of a second
+// debit.
+func TestLedgerAdapterDedupesAcrossRestart(t *testing.T) {
+	l := newTestAdapterLedger(t)
+	refPath := filepath.Join(t.TempDir(), "applied-refs.txt")
+
+	refs1, err := OpenFileRefStore(refPath)
+	if err != nil {
+		t.Fatalf("OpenFileRefStore: %v", err)
+	}
+	adapter1 := NewLedgerAdapter(l, refs1)
+
+	tx := Transaction{ID: "tx-1", From: "alice", To: "bob", Amount: 40}
+	if err := adapter1.Withdraw(tx.ID, tx.From, tx.Amount); err != nil {
+		t.Fatalf("first Withdraw: %v", err)
+	}
+	if err := refs1.Close(); err != nil {
+		t.Fatalf("close refs1: %v", err)
+	}
+	if balance, _ := l.GetBalance("alice"); balance != 60 {
+		t.Fatalf("alice balance after first withdraw = %d, want 60", balance)
+	}
+
+	// Crash: the Engine never got to persist the transition out of
+	// Withdrawing, so a resumed run re-enters it against a fresh
+	// LedgerAdapter backed by the same on-disk ref file.
+	store := NewMemoryPersistence()
+	withdrawingState := State{Transaction: tx, Status: StatusWithdrawing}
+	if _, err := store.CompareAndSwap(State{}, withdrawingState); err != nil {
+		t.Fatalf("seed checkpoint: %v", err)
+	}
+
+	refs2, err := OpenFileRefStore(refPath)
+	if err != nil {
+		t.Fatalf("reopen FileRefStore: %v", err)
+	}
+	defer refs2.Close()
+	adapter2 := NewLedgerAdapter(l, refs2)
+
+	engine := NewEngine(store, adapter2)
+	if err := engine.Resume(); err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+
+	if balance, _ := l.GetBalance("alice"); balance != 60 {
+		t.Fatalf("alice balance after resume = %d, want 60 (no double debit)", balance)
+	}
+	if balance, _ := l.GetBalance("bob"); balance != 40 {
+		t.Fatalf("bob balance after resume = %d, want 40", balance)
+	}
+	state, ok, err := store.Load(tx.ID)
+	if err != nil || !ok || state.Status != StatusSucceeded {
+		t.Fatalf("state after resume = %+v, ok=%v, err=%v", state, ok, err)
+	}
+}
@@ -0,0 +1,148 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// MemoryPersistence keeps all state in process memory. It satisfies
+// Persistence and is useful for tests and for demos that don't need to
+// survive a restart.
+type MemoryPersistence struct {
+	mu     sync.Mutex
+	states map[string]State
+}
+
+// NewMemoryPersistence returns an empty, ready-to-use MemoryPersistence.
+func NewMemoryPersistence() *MemoryPersistence {
+	return &MemoryPersistence{states: make(map[string]State)}
+}
+
+func (p *MemoryPersistence) Load(id string) (State, bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	state, ok := p.states[id]
+	return state, ok, nil
+}
+
+func (p *MemoryPersistence) CompareAndSwap(prev, next State) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	id := next.Transaction.ID
+	current, ok := p.states[id]
+	if ok && current.Status != prev.Status {
+		return false, nil
+	}
+	if !ok && prev.Status != "" {
+		return false, nil
+	}
+	p.states[id] = next
+	return true, nil
+}
+
+func (p *MemoryPersistence) List() ([]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ids := make([]string, 0, len(p.states))
+	for id, state := range p.states {
+		if state.Status != StatusSucceeded && state.Status != StatusFailed {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// FilePersistence checkpoints each transaction to its own JSON file under
+// Dir, so a process restart can recover in-flight transactions from disk
+// without a database. It's a lighter-weight alternative to
+// SQLitePersistence for demos and tests that don't want to carry a SQLite
+// file around.
+type FilePersistence struct {
+	mu  sync.Mutex
+	Dir string
+}
+
+// NewFilePersistence ensures Dir exists and returns a FilePersistence rooted
+// there.
+func NewFilePersistence(dir string) (*FilePersistence, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("workflow: create persistence dir: %w", err)
+	}
+	return &FilePersistence{Dir: dir}, nil
+}
+
+func (p *FilePersistence) path(id string) string {
+	return filepath.Join(p.Dir, id+".json")
+}
+
+func (p *FilePersistence) Load(id string) (State, bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.load(id)
+}
+
+func (p *FilePersistence) load(id string) (State, bool, error) {
+	data, err := os.ReadFile(p.path(id))
+	if os.IsNotExist(err) {
+		return State{}, false, nil
+	}
+	if err != nil {
+		return State{}, false, fmt.Errorf("workflow: read checkpoint %s: %w", id, err)
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return State{}, false, fmt.Errorf("workflow: decode checkpoint %s: %w", id, err)
+	}
+	return state, true, nil
+}
+
+func (p *FilePersistence) CompareAndSwap(prev, next State) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	id := next.Transaction.ID
+	current, ok, err := p.load(id)
+	if err != nil {
+		return false, err
+	}
+	if ok && current.Status != prev.Status {
+		return false, nil
+	}
+	if !ok && prev.Status != "" {
+		return false, nil
+	}
+	data, err := json.Marshal(next)
+	if err != nil {
+		return false, fmt.Errorf("workflow: encode checkpoint %s: %w", id, err)
+	}
+	if err := os.WriteFile(p.path(id), data, 0o644); err != nil {
+		return false, fmt.Errorf("workflow: write checkpoint %s: %w", id, err)
+	}
+	return true, nil
+}
+
+func (p *FilePersistence) List() ([]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entries, err := os.ReadDir(p.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("workflow: list checkpoint dir: %w", err)
+	}
+	var ids []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		id := entry.Name()[:len(entry.Name())-len(filepath.Ext(entry.Name()))]
+		state, ok, err := p.load(id)
+		if err != nil {
+			return nil, err
+		}
+		if ok && state.Status != StatusSucceeded && state.Status != StatusFailed {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,115 @@
+package workflow
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" database/sql driver
+)
+
+// SQLitePersistence checkpoints every transaction to a SQLite database via
+// modernc.org/sqlite (a CGo-free driver, so it needs no system SQLite
+// library to build or run), giving the engine real on-disk durability
+// across a process restart.
+type SQLitePersistence struct {
+	db *sql.DB
+}
+
+// OpenSQLitePersistence opens (creating if necessary) the SQLite database
+// at path and ensures its schema exists.
+func OpenSQLitePersistence(path string) (*SQLitePersistence, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("workflow: open sqlite persistence: %w", err)
+	}
+	const schema = `
+		CREATE TABLE IF NOT EXISTS workflow_state (
+			id           TEXT PRIMARY KEY,
+			from_account TEXT NOT NULL,
+			to_account   TEXT NOT NULL,
+			amount       INTEGER NOT NULL,
+			status       TEXT NOT NULL,
+			last_err     TEXT NOT NULL DEFAULT ''
+		)`
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("workflow: create schema: %w", err)
+	}
+	return &SQLitePersistence{db: db}, nil
+}
+
+// Close releases the underlying database handle.
+func (p *SQLitePersistence) Close() error {
+	return p.db.Close()
+}
+
+func (p *SQLitePersistence) Load(id string) (State, bool, error) {
+	var state State
+	var status string
+	row := p.db.QueryRow(
+		`SELECT from_account, to_account, amount, status, last_err FROM workflow_state WHERE id = ?`, id)
+	err := row.Scan(&state.Transaction.From, &state.Transaction.To, &state.Transaction.Amount, &status, &state.LastErr)
+	if err == sql.ErrNoRows {
+		return State{}, false, nil
+	}
+	if err != nil {
+		return State{}, false, fmt.Errorf("workflow: load %s: %w", id, err)
+	}
+	state.Transaction.ID = id
+	state.Status = Status(status)
+	return state, true, nil
+}
+
+// CompareAndSwap inserts next if prev.Status is empty (no prior checkpoint),
+// or conditionally updates it when the stored row's status still matches
+// prev.Status. Either way the write is a single atomic statement, so two
+// engines racing to persist the same transition can't both succeed.
+func (p *SQLitePersistence) CompareAndSwap(prev, next State) (bool, error) {
+	tx := next.Transaction
+	if prev.Status == "" {
+		res, err := p.db.Exec(
+			`INSERT OR IGNORE INTO workflow_state (id, from_account, to_account, amount, status, last_err) VALUES (?, ?, ?, ?, ?, ?)`,
+			tx.ID, tx.From, tx.To, tx.Amount, string(next.Status), next.LastErr,
+		)
+		if err != nil {
+			return false, fmt.Errorf("workflow: insert checkpoint %s: %w", tx.ID, err)
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return false, fmt.Errorf("workflow: insert checkpoint %s: %w", tx.ID, err)
+		}
+		return rows == 1, nil
+	}
+
+	res, err := p.db.Exec(
+		`UPDATE workflow_state SET status = ?, last_err = ? WHERE id = ? AND status = ?`,
+		string(next.Status), next.LastErr, tx.ID, string(prev.Status),
+	)
+	if err != nil {
+		return false, fmt.Errorf("workflow: update checkpoint %s: %w", tx.ID, err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("workflow: update checkpoint %s: %w", tx.ID, err)
+	}
+	return rows == 1, nil
+}
+
+func (p *SQLitePersistence) List() ([]string, error) {
+	rows, err := p.db.Query(
+		`SELECT id FROM workflow_state WHERE status NOT IN (?, ?)`, string(StatusSucceeded), string(StatusFailed))
+	if err != nil {
+		return nil, fmt.Errorf("workflow: list in-flight: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("workflow: list in-flight: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,233 @@
+// Package workflow turns a transfer (withdraw-then-deposit) into a durable,
+// restartable state machine. Each step is checkpointed through a pluggable
+// Persistence implementation so that a process crash mid-transfer can be
+// resumed from the last known state instead of being silently lost, and a
+// failed deposit leg triggers a compensating withdrawal refund (a saga
+// rollback) rather than leaving the books unbalanced.
+package workflow
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Status is the lifecycle state of a Transaction as it moves through the
+// engine. Transitions are strictly forward except for the Refunding
+// compensation path, which runs after Depositing fails.
+type Status string
+
+const (
+	StatusStarted     Status = "Started"
+	StatusWithdrawing Status = "Withdrawing"
+	StatusDepositing  Status = "Depositing"
+	StatusRefunding   Status = "Refunding"
+	StatusSucceeded   Status = "Succeeded"
+	StatusFailed      Status = "Failed"
+)
+
+// Non-retryable errors move a transaction straight to Failed: retrying an
+// insufficient-funds or unknown-account error can never succeed. Any other
+// withdraw error is returned with the transaction left at its last
+// checkpoint, so a later Submit or Resume runs the step again; the engine
+// does not retry within a single call.
+var (
+	ErrInsufficientFunds = errors.New("workflow: insufficient funds")
+	ErrAccountNotFound   = errors.New("workflow: account not found")
+)
+
+func isRetryable(err error) bool {
+	return err != nil && !errors.Is(err, ErrInsufficientFunds) && !errors.Is(err, ErrAccountNotFound)
+}
+
+// Transaction is a single transfer request, keyed by a caller-supplied
+// reference ID. The ID is the idempotency key: replaying the same
+// Transaction after a crash resumes the existing run instead of re-applying
+// the withdraw or deposit legs.
+type Transaction struct {
+	ID     string // idempotency / reference ID
+	From   string
+	To     string
+	Amount int
+}
+
+// State is the persisted checkpoint for an in-flight or finished
+// Transaction.
+type State struct {
+	Transaction Transaction
+	Status      Status
+	LastErr     string
+}
+
+// Persistence is the pluggable store behind the engine. CompareAndSwap must
+// only succeed when the stored state's Status still matches prev.Status,
+// giving the engine a single-writer guarantee per transaction ID even if two
+// engines race to resume the same one after a restart.
+type Persistence interface {
+	Load(id string) (State, bool, error)
+	CompareAndSwap(prev, next State) (bool, error)
+	// List returns the IDs of every transaction that has not reached a
+	// terminal status, so the engine can resume them after a restart.
+	List() ([]string, error)
+}
+
+// Ledger is the minimal set of operations the engine needs to drive a
+// transfer. Withdraw and Deposit must be idempotent for a given reference
+// ID so that a replayed step after a crash doesn't double-debit.
+type Ledger interface {
+	Withdraw(ref, account string, amount int) error
+	Deposit(ref, account string, amount int) error
+}
+
+// Engine drives Transactions through their saga, checkpointing every
+// transition to Store.
+type Engine struct {
+	Store  Persistence
+	Ledger Ledger
+}
+
+// NewEngine wires a Persistence implementation and the Ledger it should
+// drive transfers against.
+func NewEngine(store Persistence, ledger Ledger) *Engine {
+	return &Engine{Store: store, Ledger: ledger}
+}
+
+// Submit runs tx to completion (or to a terminal Failed state), checkpointing
+// each step. Calling Submit again with the same tx.ID resumes from whatever
+// state was last persisted rather than starting over.
+func (e *Engine) Submit(tx Transaction) error {
+	state, ok, err := e.Store.Load(tx.ID)
+	if err != nil {
+		return fmt.Errorf("workflow: load %s: %w", tx.ID, err)
+	}
+	if !ok {
+		state = State{Transaction: tx, Status: StatusStarted}
+		if _, err := e.Store.CompareAndSwap(State{}, state); err != nil {
+			return fmt.Errorf("workflow: checkpoint start %s: %w", tx.ID, err)
+		}
+	}
+	return e.run(state)
+}
+
+// Resume re-scans the persisted state and continues any transaction that
+// did not reach a terminal status, picking up from its last checkpoint.
+// Call this once on process startup before accepting new submissions.
+func (e *Engine) Resume() error {
+	ids, err := e.Store.List()
+	if err != nil {
+		return fmt.Errorf("workflow: list in-flight: %w", err)
+	}
+	for _, id := range ids {
+		state, ok, err := e.Store.Load(id)
+		if err != nil {
+			return fmt.Errorf("workflow: load %s: %w", id, err)
+		}
+		if !ok {
+			continue
+		}
+		if err := e.run(state); err != nil {
+			return fmt.Errorf("workflow: resume %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+func (e *Engine) run(state State) error {
+	for {
+		switch state.Status {
+		case StatusStarted:
+			next, err := e.transition(state, StatusWithdrawing)
+			if err != nil {
+				return err
+			}
+			state = next
+		case StatusWithdrawing:
+			tx := state.Transaction
+			if err := e.Ledger.Withdraw(tx.ID, tx.From, tx.Amount); err != nil {
+				if !isRetryable(err) {
+					return e.fail(state, err)
+				}
+				return fmt.Errorf("workflow: withdraw %s: %w", tx.ID, err)
+			}
+			next, err := e.transition(state, StatusDepositing)
+			if err != nil {
+				return err
+			}
+			state = next
+		case StatusDepositing:
+			tx := state.Transaction
+			if err := e.Ledger.Deposit(tx.ID, tx.To, tx.Amount); err != nil {
+				// The withdraw leg already committed, so a failed deposit
+				// must be compensated with a refund rather than left
+				// half-applied.
+				prev := state
+				refunding := state
+				refunding.Status = StatusRefunding
+				refunding.LastErr = err.Error()
+				next, cErr := e.checkpoint(prev, refunding)
+				if cErr != nil {
+					return fmt.Errorf("workflow: checkpoint refund %s: %w", tx.ID, cErr)
+				}
+				state = next
+				continue
+			}
+			next, err := e.transition(state, StatusSucceeded)
+			if err != nil {
+				return err
+			}
+			state = next
+			return nil
+		case StatusRefunding:
+			tx := state.Transaction
+			if err := e.Ledger.Deposit(tx.ID+":refund", tx.From, tx.Amount); err != nil {
+				return fmt.Errorf("workflow: compensating refund %s: %w", tx.ID, err)
+			}
+			return e.fail(state, errors.New(state.LastErr))
+		case StatusSucceeded, StatusFailed:
+			return nil
+		default:
+			return fmt.Errorf("workflow: unknown status %q for %s", state.Status, state.Transaction.ID)
+		}
+	}
+}
+
+// transition advances state to next and checkpoints the result.
+func (e *Engine) transition(state State, next Status) (State, error) {
+	prev := state
+	state.Status = next
+	return e.checkpoint(prev, state)
+}
+
+// checkpoint persists next via CompareAndSwap, guarded by prev's status.
+// If another engine already advanced this transaction past prev (ok is
+// false, not an error), checkpoint reloads and returns whatever was
+// actually persisted instead of silently proceeding as if next had been
+// saved — that single-writer guarantee is the entire point of CAS-backed
+// persistence.
+func (e *Engine) checkpoint(prev, next State) (State, error) {
+	ok, err := e.Store.CompareAndSwap(prev, next)
+	if err != nil {
+		return State{}, fmt.Errorf("workflow: checkpoint %s: %w", next.Transaction.ID, err)
+	}
+	if !ok {
+		reloaded, found, lErr := e.Store.Load(next.Transaction.ID)
+		if lErr != nil {
+			return State{}, fmt.Errorf("workflow: reload %s after lost checkpoint: %w", next.Transaction.ID, lErr)
+		}
+		if !found {
+			return State{}, fmt.Errorf("workflow: lost checkpoint for %s and nothing persisted to reload", next.Transaction.ID)
+		}
+		return reloaded, nil
+	}
+	return next, nil
+}
+
+func (e *Engine) fail(state State, err error) error {
+	prev := state
+	next := state
+	next.Status = StatusFailed
+	next.LastErr = err.Error()
+	if _, cErr := e.checkpoint(prev, next); cErr != nil {
+		return fmt.Errorf("workflow: checkpoint failure for %s: %w", state.Transaction.ID, cErr)
+	}
+	return err
+}
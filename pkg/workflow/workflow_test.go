@@ -0,0 +1,126 @@
+package workflow
+
+import (
+	"errors"
+	"testing"
+)
+
+// fakeLedger lets a test fail the deposit leg on demand and records every
+// ref it was called with, so a test can assert a crash-then-resume replay
+// doesn't call Withdraw/Deposit twice for the same ref.
+type fakeLedger struct {
+	failDeposit map[string]bool
+	withdrawn   []string
+	deposited   []string
+	balances    map[string]int
+}
+
+func newFakeLedger(balances map[string]int) *fakeLedger {
+	return &fakeLedger{failDeposit: make(map[string]bool), balances: balances}
+}
+
+func (l *fakeLedger) Withdraw(ref, account string, amount int) error {
+	if l.balances[account] < amount {
+		return ErrInsufficientFunds
+	}
+	l.balances[account] -= amount
+	l.withdrawn = append(l.withdrawn, ref)
+	return nil
+}
+
+func (l *fakeLedger) Deposit(ref, account string, amount int) error {
+	if l.failDeposit[ref] {
+		return errors.New("deposit leg unavailable")
+	}
+	l.balances[account] += amount
+	l.deposited = append(l.deposited, ref)
+	return nil
+}
+
+func TestSubmitSucceeds(t *testing.T) {
+	store := NewMemoryPersistence()
+	ledger := newFakeLedger(map[string]int{"alice": 100, "bob": 0})
+	engine := NewEngine(store, ledger)
+
+	tx := Transaction{ID: "tx-1", From: "alice", To: "bob", Amount: 40}
+	if err := engine.Submit(tx); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	if ledger.balances["alice"] != 60 || ledger.balances["bob"] != 40 {
+		t.Fatalf("unexpected balances: %+v", ledger.balances)
+	}
+	state, ok, err := store.Load("tx-1")
+	if err != nil || !ok {
+		t.Fatalf("Load: ok=%v err=%v", ok, err)
+	}
+	if state.Status != StatusSucceeded {
+		t.Fatalf("status = %s, want Succeeded", state.Status)
+	}
+}
+
+func TestResumeAfterSimulatedCrash(t *testing.T) {
+	store := NewMemoryPersistence()
+	ledger := newFakeLedger(map[string]int{"alice": 100, "bob": 0})
+
+	// Simulate a crash right after the withdraw leg checkpointed but
+	// before the engine got to run the deposit leg: persist the
+	// Depositing state directly, as if a prior process had reached it and
+	// died before continuing.
+	tx := Transaction{ID: "tx-2", From: "alice", To: "bob", Amount: 40}
+	withdrawnState := State{Transaction: tx, Status: StatusDepositing}
+	if _, err := store.CompareAndSwap(State{}, withdrawnState); err != nil {
+		t.Fatalf("seed checkpoint: %v", err)
+	}
+	ledger.balances["alice"] -= 40 // mirror the withdraw the crashed run already applied
+
+	engine := NewEngine(store, ledger)
+	if err := engine.Resume(); err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+
+	if len(ledger.withdrawn) != 0 {
+		t.Fatalf("resume re-ran the withdraw leg: %v", ledger.withdrawn)
+	}
+	if ledger.balances["bob"] != 40 {
+		t.Fatalf("bob balance = %d, want 40", ledger.balances["bob"])
+	}
+	state, ok, err := store.Load("tx-2")
+	if err != nil || !ok || state.Status != StatusSucceeded {
+		t.Fatalf("state after resume = %+v, ok=%v, err=%v", state, ok, err)
+	}
+}
+
+func TestDepositFailureCompensatesWithRefund(t *testing.T) {
+	store := NewMemoryPersistence()
+	ledger := newFakeLedger(map[string]int{"alice": 100, "bob": 0})
+	ledger.failDeposit["tx-3"] = true
+
+	engine := NewEngine(store, ledger)
+	tx := Transaction{ID: "tx-3", From: "alice", To: "bob", Amount: 40}
+	if err := engine.Submit(tx); err == nil {
+		t.Fatal("Submit: expected an error from the failed deposit leg")
+	}
+
+	if ledger.balances["alice"] != 100 {
+		t.Fatalf("alice balance = %d, want 100 (refunded)", ledger.balances["alice"])
+	}
+	state, ok, err := store.Load("tx-3")
+	if err != nil || !ok || state.Status != StatusFailed {
+		t.Fatalf("state = %+v, ok=%v, err=%v", state, ok, err)
+	}
+}
+
+func TestSubmitShortCircuitsOnInsufficientFunds(t *testing.T) {
+	store := NewMemoryPersistence()
+	ledger := newFakeLedger(map[string]int{"alice": 10, "bob": 0})
+	engine := NewEngine(store, ledger)
+
+	tx := Transaction{ID: "tx-4", From: "alice", To: "bob", Amount: 40}
+	if err := engine.Submit(tx); !errors.Is(err, ErrInsufficientFunds) {
+		t.Fatalf("Submit error = %v, want ErrInsufficientFunds", err)
+	}
+	state, ok, err := store.Load("tx-4")
+	if err != nil || !ok || state.Status != StatusFailed {
+		t.Fatalf("state = %+v, ok=%v, err=%v", state, ok, err)
+	}
+}